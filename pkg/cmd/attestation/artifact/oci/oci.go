@@ -0,0 +1,224 @@
+// Package oci provides a Client for resolving artifact digests and
+// attestation-related metadata from OCI registries, used by
+// `gh attestation verify` when given an oci:// reference.
+package oci
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// PlatformManifest describes a single child manifest of an OCI image index
+// (or Docker manifest list), scoped to one platform.
+type PlatformManifest struct {
+	Platform string // e.g. "linux/amd64"
+	Ref      string // oci:// reference pinned to this manifest's digest
+}
+
+// Client resolves artifact digests from an OCI registry. ResolveIndex and
+// ListReferrers are optional, narrower capabilities that callers type-assert
+// for: a Client without ResolveIndex is treated as resolving every reference
+// to a single manifest, and --referrers fails with an error when the Client
+// doesn't implement ListReferrers.
+type Client interface {
+	// GetImageDigest returns the digest of the manifest referenced by
+	// imgName.
+	GetImageDigest(imgName string) (*v1.Hash, error)
+}
+
+// LiveClient is the default Client implementation, backed by a real OCI
+// registry reached over HTTPS.
+type LiveClient struct{}
+
+// NewLiveClient returns a Client that talks to real OCI registries, using
+// the local Docker/podman credential store for auth.
+func NewLiveClient() *LiveClient {
+	return &LiveClient{}
+}
+
+func (c *LiveClient) GetImageDigest(imgName string) (*v1.Hash, error) {
+	ref, err := parseReference(imgName)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := remote.Get(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest for %s: %w", imgName, err)
+	}
+
+	return &desc.Digest, nil
+}
+
+// ResolveIndex reports whether ref points to an OCI image index or Docker
+// manifest list and, if so, enumerates its child manifests by platform. It
+// satisfies the indexResolver interface declared in the verify package.
+func (c *LiveClient) ResolveIndex(ref string) ([]PlatformManifest, bool, error) {
+	parsed, err := parseReference(ref)
+	if err != nil {
+		return nil, false, err
+	}
+
+	desc, err := remote.Get(parsed, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch manifest for %s: %w", ref, err)
+	}
+	if !desc.MediaType.IsIndex() {
+		return nil, false, nil
+	}
+
+	idx, err := desc.ImageIndex()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse image index for %s: %w", ref, err)
+	}
+	indexManifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read index manifest for %s: %w", ref, err)
+	}
+
+	repo := parsed.Context()
+	var manifests []PlatformManifest
+	for _, m := range indexManifest.Manifests {
+		if m.Platform == nil {
+			continue
+		}
+		manifests = append(manifests, PlatformManifest{
+			Platform: m.Platform.OS + "/" + m.Platform.Architecture,
+			Ref:      "oci://" + repo.String() + "@" + m.Digest.String(),
+		})
+	}
+	return manifests, true, nil
+}
+
+// ListReferrers uses the OCI 1.1 Referrers API to discover artifacts (such
+// as attestations) whose `subject` field points at ref. It satisfies the
+// referrersLister interface declared in the verify package.
+func (c *LiveClient) ListReferrers(ref string) ([]string, error) {
+	parsed, err := parseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, ok := parsed.(name.Digest)
+	if !ok {
+		desc, err := remote.Get(parsed, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve digest for %s: %w", ref, err)
+		}
+		digest = parsed.Context().Digest(desc.Digest.String())
+	}
+
+	referrers, err := remote.Referrers(digest, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list referrers for %s: %w", ref, err)
+	}
+	referrersManifest, err := referrers.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read referrers index for %s: %w", ref, err)
+	}
+
+	repo := parsed.Context()
+	refs := make([]string, 0, len(referrersManifest.Manifests))
+	for _, m := range referrersManifest.Manifests {
+		refs = append(refs, "oci://"+repo.String()+"@"+m.Digest.String())
+	}
+	return refs, nil
+}
+
+// FetchReferrerBundle pulls the manifest discovered via ListReferrers and
+// returns the raw bytes of its first layer, which holds the attestation
+// bundle attached to the subject. It satisfies the referrerBundleFetcher
+// interface declared in the verify package.
+func (c *LiveClient) FetchReferrerBundle(ref string) ([]byte, error) {
+	parsed, err := parseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := remote.Image(parsed, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch referrer manifest for %s: %w", ref, err)
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read referrer layers for %s: %w", ref, err)
+	}
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("referrer manifest %s has no layers", ref)
+	}
+
+	rc, err := layers[0].Uncompressed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read referrer blob for %s: %w", ref, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read referrer blob for %s: %w", ref, err)
+	}
+	return data, nil
+}
+
+func parseReference(ref string) (name.Reference, error) {
+	parsed, err := name.ParseReference(strings.TrimPrefix(ref, "oci://"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OCI reference %s: %w", ref, err)
+	}
+	return parsed, nil
+}
+
+// MockClient is a Client implementation for tests. Each method delegates to
+// an overridable func field; a zero-value MockClient resolves every
+// reference as a single-platform manifest with no referrers, i.e. the same
+// behavior as a registry that only understands plain image manifests.
+type MockClient struct {
+	OnGetImageDigest      func(imgName string) (*v1.Hash, error)
+	OnResolveIndex        func(ref string) ([]PlatformManifest, bool, error)
+	OnListReferrers       func(ref string) ([]string, error)
+	OnFetchReferrerBundle func(ref string) ([]byte, error)
+}
+
+func (c MockClient) GetImageDigest(imgName string) (*v1.Hash, error) {
+	if c.OnGetImageDigest == nil {
+		return nil, fmt.Errorf("GetImageDigest not configured on MockClient")
+	}
+	return c.OnGetImageDigest(imgName)
+}
+
+func (c MockClient) ResolveIndex(ref string) ([]PlatformManifest, bool, error) {
+	if c.OnResolveIndex == nil {
+		return nil, false, nil
+	}
+	return c.OnResolveIndex(ref)
+}
+
+func (c MockClient) ListReferrers(ref string) ([]string, error) {
+	if c.OnListReferrers == nil {
+		return nil, fmt.Errorf("no referrers configured on MockClient")
+	}
+	return c.OnListReferrers(ref)
+}
+
+func (c MockClient) FetchReferrerBundle(ref string) ([]byte, error) {
+	if c.OnFetchReferrerBundle == nil {
+		return nil, fmt.Errorf("no referrer bundle configured on MockClient for %s", ref)
+	}
+	return c.OnFetchReferrerBundle(ref)
+}
+
+// ReferenceFailClient is a Client implementation for tests that always fails
+// to resolve a reference, simulating an unreachable or misconfigured
+// registry.
+type ReferenceFailClient struct{}
+
+func (c ReferenceFailClient) GetImageDigest(imgName string) (*v1.Hash, error) {
+	return nil, fmt.Errorf("failed to resolve reference %s", imgName)
+}
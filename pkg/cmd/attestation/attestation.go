@@ -0,0 +1,22 @@
+package attestation
+
+import (
+	"github.com/cli/cli/v2/pkg/cmd/attestation/trustroot"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/verify"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdAttestation creates the `gh attestation` command group, mounting
+// `verify` and `trust-root` as sibling subcommands.
+func NewCmdAttestation(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attestation <command>",
+		Short: "Work with artifact attestations",
+	}
+
+	cmd.AddCommand(verify.NewVerifyCmd(f, nil))
+	cmd.AddCommand(trustroot.NewCmdTrustRoot(f))
+
+	return cmd
+}
@@ -0,0 +1,28 @@
+package attestation
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdAttestation(t *testing.T) {
+	testIO, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{IOStreams: testIO}
+
+	cmd := NewCmdAttestation(f)
+
+	verifyCmd, _, err := cmd.Find([]string{"verify"})
+	require.NoError(t, err)
+	require.Equal(t, "verify", verifyCmd.Name())
+
+	trustRootCmd, _, err := cmd.Find([]string{"trust-root"})
+	require.NoError(t, err)
+	require.Equal(t, "trust-root", trustRootCmd.Name())
+
+	refreshCmd, _, err := cmd.Find([]string{"trust-root", "refresh"})
+	require.NoError(t, err)
+	require.Equal(t, "refresh", refreshCmd.Name())
+}
@@ -0,0 +1,80 @@
+package trustroot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/io"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+
+	"github.com/sigstore/sigstore-go/pkg/tuf"
+	"github.com/spf13/cobra"
+)
+
+const trustedRootFileName = "trusted_root.json"
+
+// Options captures the inputs needed to refresh the local TUF trust root
+// cache used by `gh attestation verify --offline`.
+type Options struct {
+	CacheDir string
+
+	Logger *io.Handler
+}
+
+// NewRefreshCmd creates the `gh attestation trust-root refresh` command.
+func NewRefreshCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Fetch the current Sigstore TUF trust root and cache it for offline verification",
+		Long: "Pull the current Sigstore TUF root once and write it to a local cache directory. " +
+			"Pass the same directory to `gh attestation verify --tuf-cache-dir` to verify attestations " +
+			"fully offline afterwards.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.CacheDir == "" {
+				return cmdutil.FlagErrorf("`--tuf-cache-dir` is required")
+			}
+
+			opts.Logger = io.NewHandler(f.IOStreams)
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return runRefresh(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.CacheDir, "tuf-cache-dir", "", "Directory to write the cached TUF trust root to")
+
+	return cmd
+}
+
+// runRefresh fetches the current TUF root from the Sigstore TUF mirror and
+// writes the resulting trusted_root.json to opts.CacheDir, for later use by
+// `gh attestation verify --offline --tuf-cache-dir`.
+func runRefresh(opts *Options) error {
+	if err := os.MkdirAll(opts.CacheDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create TUF cache directory: %w", err)
+	}
+
+	client, err := tuf.New(tuf.DefaultOptions())
+	if err != nil {
+		return fmt.Errorf("failed to initialize TUF client: %w", err)
+	}
+
+	trustedRoot, err := client.GetTarget("trusted_root.json")
+	if err != nil {
+		return fmt.Errorf("failed to fetch trusted root from TUF: %w", err)
+	}
+
+	destPath := filepath.Join(opts.CacheDir, trustedRootFileName)
+	if err := os.WriteFile(destPath, trustedRoot, 0o600); err != nil {
+		return fmt.Errorf("failed to write trusted root to %s: %w", destPath, err)
+	}
+
+	opts.Logger.Printf("Cached Sigstore trust root at %s\n", destPath)
+	return nil
+}
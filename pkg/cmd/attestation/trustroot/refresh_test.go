@@ -0,0 +1,63 @@
+package trustroot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRefreshCmd(t *testing.T) {
+	testIO, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{IOStreams: testIO}
+
+	testcases := []struct {
+		name     string
+		cli      string
+		wants    Options
+		wantsErr bool
+	}{
+		{
+			name:     "Missing tuf-cache-dir flag",
+			cli:      "",
+			wantsErr: true,
+		},
+		{
+			name: "Uses tuf-cache-dir flag",
+			cli:  "--tuf-cache-dir /tmp/gh-attestation-tuf-cache",
+			wants: Options{
+				CacheDir: "/tmp/gh-attestation-tuf-cache",
+			},
+			wantsErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts *Options
+			cmd := NewRefreshCmd(f, func(o *Options) error {
+				opts = o
+				return nil
+			})
+
+			var argv []string
+			if tc.cli != "" {
+				argv = strings.Split(tc.cli, " ")
+			}
+			cmd.SetArgs(argv)
+			cmd.SetIn(&bytes.Buffer{})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			_, err := cmd.ExecuteC()
+			if tc.wantsErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wants.CacheDir, opts.CacheDir)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package trustroot
+
+import (
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdTrustRoot creates the `gh attestation trust-root` command group,
+// which manages the local cache of Sigstore trust material used by
+// `gh attestation verify --offline`. It is mounted on the `attestation` root
+// command alongside NewVerifyCmd.
+func NewCmdTrustRoot(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trust-root",
+		Short: "Manage the local Sigstore trust root cache used for offline verification",
+	}
+
+	cmd.AddCommand(NewRefreshCmd(f, nil))
+
+	return cmd
+}
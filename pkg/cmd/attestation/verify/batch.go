@@ -0,0 +1,179 @@
+package verify
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// resolveArtifactPaths expands the positional arguments passed to `gh
+// attestation verify` into a flat list of artifact paths. Each argument may
+// be a plain file path, an oci:// reference, a glob pattern, a directory
+// (expanded recursively), or an @filelist.txt reference containing one path
+// per line.
+func resolveArtifactPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "@"):
+			listPaths, err := readArtifactList(strings.TrimPrefix(arg, "@"))
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, listPaths...)
+		case strings.HasPrefix(arg, "oci://"):
+			paths = append(paths, arg)
+		case strings.ContainsAny(arg, "*?["):
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to expand glob %s: %w", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("glob %s matched no files", arg)
+			}
+			paths = append(paths, matches...)
+		default:
+			expanded, err := expandIfDirectory(arg)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, expanded...)
+		}
+	}
+	return paths, nil
+}
+
+func readArtifactList(listPath string) ([]string, error) {
+	f, err := os.Open(listPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open artifact list %s: %w", listPath, err)
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read artifact list %s: %w", listPath, err)
+	}
+	return paths, nil
+}
+
+func expandIfDirectory(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var paths []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", path, err)
+	}
+	return paths, nil
+}
+
+// batchResult is the outcome of verifying a single artifact within a batch.
+type batchResult struct {
+	path    string
+	digest  string
+	results []*VerifiedPredicateResult
+	err     error
+}
+
+// runBatchVerify fans out verification of every distinct artifact reference
+// in paths across a bounded worker pool, reusing opts.APIClient and
+// opts.OCIClient across workers. A reference repeated more than once in
+// paths (e.g. the same oci:// image passed twice) is only digested and
+// fetched once; its result is reused for every occurrence. It aggregates
+// each artifact's results keyed by subject digest and keeps verifying the
+// remaining artifacts even if one fails, returning a combined error only
+// once every artifact has been attempted.
+func runBatchVerify(opts *Options, paths []string) error {
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	uniquePaths := dedupePaths(paths)
+
+	resultCh := make(chan batchResult, len(uniquePaths))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+
+	for _, path := range uniquePaths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			digest, results, err := verifyArtifact(opts, path)
+			resultCh <- batchResult{path: path, digest: digest, results: results, err: err}
+		}(path)
+	}
+
+	wg.Wait()
+	close(resultCh)
+
+	resultsByPath := make(map[string]batchResult, len(uniquePaths))
+	for r := range resultCh {
+		resultsByPath[r.path] = r
+	}
+
+	combined := map[string][]*VerifiedPredicateResult{}
+	var failures []error
+	for _, path := range paths {
+		r := resultsByPath[path]
+		if r.err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", path, r.err))
+			continue
+		}
+		combined[r.digest] = append(combined[r.digest], r.results...)
+		opts.Logger.Printf("Verified %d attestation(s) for %s (digest %s)\n", len(r.results), path, r.digest)
+	}
+
+	if opts.exporter != nil {
+		if err := opts.exporter.Write(opts.Logger.IO, combined); err != nil {
+			return err
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.Join(failures...)
+	}
+	return nil
+}
+
+// dedupePaths returns paths with duplicates removed, preserving the order of
+// first occurrence.
+func dedupePaths(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	unique := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		unique = append(unique, p)
+	}
+	return unique
+}
@@ -0,0 +1,105 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact/oci"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/io"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// countingOCIClient wraps oci.MockClient and records how many times each
+// reference was digested, so tests can assert on de-duplication.
+type countingOCIClient struct {
+	oci.MockClient
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *countingOCIClient) GetImageDigest(imgName string) (*v1.Hash, error) {
+	c.mu.Lock()
+	if c.calls == nil {
+		c.calls = map[string]int{}
+	}
+	c.calls[imgName]++
+	c.mu.Unlock()
+	return c.MockClient.GetImageDigest(imgName)
+}
+
+func (c *countingOCIClient) callCount(imgName string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[imgName]
+}
+
+func TestResolveArtifactPaths(t *testing.T) {
+	t.Run("plain file paths pass through unchanged", func(t *testing.T) {
+		paths, err := resolveArtifactPaths([]string{artifactPath, bundlePath})
+		require.NoError(t, err)
+		require.Equal(t, []string{artifactPath, bundlePath}, paths)
+	})
+
+	t.Run("oci references pass through unchanged", func(t *testing.T) {
+		paths, err := resolveArtifactPaths([]string{"oci://ghcr.io/github/test"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"oci://ghcr.io/github/test"}, paths)
+	})
+
+	t.Run("directories are expanded recursively", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0o600))
+
+		paths, err := resolveArtifactPaths([]string{dir})
+		require.NoError(t, err)
+		require.Len(t, paths, 2)
+	})
+
+	t.Run("filelist references are expanded", func(t *testing.T) {
+		dir := t.TempDir()
+		listPath := filepath.Join(dir, "artifacts.txt")
+		require.NoError(t, os.WriteFile(listPath, []byte(artifactPath+"\n# a comment\n\n"+bundlePath+"\n"), 0o600))
+
+		paths, err := resolveArtifactPaths([]string{"@" + listPath})
+		require.NoError(t, err)
+		require.Equal(t, []string{artifactPath, bundlePath}, paths)
+	})
+
+	t.Run("globs that match nothing are an error", func(t *testing.T) {
+		_, err := resolveArtifactPaths([]string{filepath.Join(t.TempDir(), "*.nope")})
+		require.Error(t, err)
+	})
+}
+
+func TestRunBatchVerify_DedupesReferences(t *testing.T) {
+	client := &countingOCIClient{
+		MockClient: oci.MockClient{
+			OnGetImageDigest: func(imgName string) (*v1.Hash, error) {
+				return nil, fmt.Errorf("no real registry available for %s", imgName)
+			},
+		},
+	}
+
+	opts := &Options{
+		Jobs:      2,
+		Logger:    io.NewTestHandler(),
+		OCIClient: client,
+	}
+
+	paths := []string{
+		"oci://ghcr.io/github/test",
+		"oci://ghcr.io/github/test",
+		"oci://ghcr.io/github/other",
+	}
+
+	err := runBatchVerify(opts, paths)
+	require.Error(t, err)
+	require.Equal(t, 1, client.callCount("oci://ghcr.io/github/test"))
+	require.Equal(t, 1, client.callCount("oci://ghcr.io/github/other"))
+}
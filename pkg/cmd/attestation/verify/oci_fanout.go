@@ -0,0 +1,150 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/api"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact/oci"
+)
+
+// platformManifest describes a single child manifest of an OCI image index
+// (or Docker manifest list), scoped to one platform. It's an alias for
+// oci.PlatformManifest so that oci.Client implementations can satisfy
+// indexResolver without this package and the oci package importing each
+// other.
+type platformManifest = oci.PlatformManifest
+
+// indexResolver is implemented by OCI clients that can tell an image index /
+// manifest list apart from a single-platform image and enumerate its child
+// manifests. oci.Client implementations that don't support it are treated as
+// always resolving to a single, non-index manifest.
+type indexResolver interface {
+	ResolveIndex(ref string) (manifests []platformManifest, isIndex bool, err error)
+}
+
+// referrersLister is implemented by OCI clients that can query the OCI 1.1
+// Referrers API for attestations attached to a subject digest via the
+// `subject` field, instead of requiring a separate --bundle.
+type referrersLister interface {
+	ListReferrers(ref string) (bundleRefs []string, err error)
+}
+
+// referrerBundleFetcher is implemented by OCI clients that can pull the raw
+// attestation bundle bytes for a reference discovered via referrersLister.
+type referrerBundleFetcher interface {
+	FetchReferrerBundle(ref string) (bundle []byte, err error)
+}
+
+// expandOCIReferences fans `oci://` references that resolve to an image
+// index / manifest list out into one reference per child manifest, filtered
+// by --platform/--all-platforms. Plain file paths and single-platform OCI
+// references pass through unchanged.
+func expandOCIReferences(opts *Options, paths []string) ([]string, error) {
+	resolver, ok := opts.OCIClient.(indexResolver)
+	if !ok {
+		return paths, nil
+	}
+
+	var expanded []string
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "oci://") {
+			expanded = append(expanded, path)
+			continue
+		}
+
+		manifests, isIndex, err := resolver.ResolveIndex(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve OCI index %s: %w", path, err)
+		}
+		if !isIndex {
+			expanded = append(expanded, path)
+			continue
+		}
+
+		matched := filterPlatforms(manifests, opts.Platforms, opts.AllPlatforms)
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("no child manifest of %s matched the requested platform(s)", path)
+		}
+		for _, m := range matched {
+			expanded = append(expanded, m.Ref)
+		}
+	}
+	return expanded, nil
+}
+
+func filterPlatforms(manifests []platformManifest, wanted []string, allPlatforms bool) []platformManifest {
+	if allPlatforms || len(wanted) == 0 {
+		return manifests
+	}
+
+	wantedSet := make(map[string]bool, len(wanted))
+	for _, p := range wanted {
+		wantedSet[p] = true
+	}
+
+	var matched []platformManifest
+	for _, m := range manifests {
+		if wantedSet[m.Platform] {
+			matched = append(matched, m)
+		}
+	}
+	return matched
+}
+
+// resolveReferrerBundles uses the OCI 1.1 Referrers API to discover bundle
+// references attached to ref via the `subject` field, in lieu of requiring
+// the caller to pass --bundle explicitly.
+func resolveReferrerBundles(opts *Options, ref string) ([]string, error) {
+	lister, ok := opts.OCIClient.(referrersLister)
+	if !ok {
+		return nil, fmt.Errorf("OCI client does not support the referrers API")
+	}
+
+	bundleRefs, err := lister.ListReferrers(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list referrers for %s: %w", ref, err)
+	}
+	if len(bundleRefs) == 0 {
+		return nil, fmt.Errorf("no attestations found via the referrers API for %s", ref)
+	}
+	return bundleRefs, nil
+}
+
+// fetchReferrerAttestations pulls the attestation bundle that bundleRef (as
+// discovered by resolveReferrerBundles) points to from the registry, and
+// parses it the same way a --bundle file on disk would be parsed.
+func fetchReferrerAttestations(opts *Options, bundleRef string) ([]*api.Attestation, error) {
+	fetcher, ok := opts.OCIClient.(referrerBundleFetcher)
+	if !ok {
+		return nil, fmt.Errorf("OCI client does not support fetching referrer bundles")
+	}
+
+	bundleBytes, err := fetcher.FetchReferrerBundle(bundleRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch referrer bundle %s: %w", bundleRef, err)
+	}
+
+	// api.AttestationsFromBundlePath only reads from disk, so materialize the
+	// bundle fetched from the registry to a temp file rather than duplicating
+	// its parsing logic here.
+	tmpFile, err := os.CreateTemp("", "gh-attestation-referrer-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file for referrer bundle %s: %w", bundleRef, err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(bundleBytes); err != nil {
+		return nil, fmt.Errorf("failed to write referrer bundle %s: %w", bundleRef, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write referrer bundle %s: %w", bundleRef, err)
+	}
+
+	attestations, err := api.AttestationsFromBundlePath(tmpFile.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse referrer bundle %s: %w", bundleRef, err)
+	}
+	return attestations, nil
+}
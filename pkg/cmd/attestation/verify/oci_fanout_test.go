@@ -0,0 +1,152 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact/oci"
+	"github.com/stretchr/testify/require"
+)
+
+// indexMockClient wraps oci.MockClient with index and referrers support, so
+// tests can exercise fan-out without a real registry.
+type indexMockClient struct {
+	oci.MockClient
+	manifests   []platformManifest
+	isIndex     bool
+	refs        []string
+	bundleBytes map[string][]byte
+}
+
+func (c indexMockClient) ResolveIndex(ref string) ([]platformManifest, bool, error) {
+	return c.manifests, c.isIndex, nil
+}
+
+func (c indexMockClient) ListReferrers(ref string) ([]string, error) {
+	if c.refs == nil {
+		return nil, errors.New("no referrers configured")
+	}
+	return c.refs, nil
+}
+
+func (c indexMockClient) FetchReferrerBundle(ref string) ([]byte, error) {
+	bundle, ok := c.bundleBytes[ref]
+	if !ok {
+		return nil, fmt.Errorf("no referrer bundle configured for %s", ref)
+	}
+	return bundle, nil
+}
+
+func TestExpandOCIReferences(t *testing.T) {
+	t.Run("non-oci paths pass through unchanged", func(t *testing.T) {
+		opts := &Options{OCIClient: indexMockClient{}}
+		paths, err := expandOCIReferences(opts, []string{artifactPath})
+		require.NoError(t, err)
+		require.Equal(t, []string{artifactPath}, paths)
+	})
+
+	t.Run("a zero-value MockClient resolves refs as single-platform manifests", func(t *testing.T) {
+		opts := &Options{OCIClient: oci.MockClient{}}
+		paths, err := expandOCIReferences(opts, []string{"oci://ghcr.io/github/test"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"oci://ghcr.io/github/test"}, paths)
+	})
+
+	t.Run("a single-platform manifest passes through unchanged", func(t *testing.T) {
+		opts := &Options{OCIClient: indexMockClient{isIndex: false}}
+		paths, err := expandOCIReferences(opts, []string{"oci://ghcr.io/github/test"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"oci://ghcr.io/github/test"}, paths)
+	})
+
+	t.Run("an index fans out to every child manifest by default", func(t *testing.T) {
+		opts := &Options{OCIClient: indexMockClient{
+			isIndex: true,
+			manifests: []platformManifest{
+				{Platform: "linux/amd64", Ref: "oci://ghcr.io/github/test@sha256:amd64"},
+				{Platform: "linux/arm64", Ref: "oci://ghcr.io/github/test@sha256:arm64"},
+			},
+		}}
+		paths, err := expandOCIReferences(opts, []string{"oci://ghcr.io/github/test"})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"oci://ghcr.io/github/test@sha256:amd64",
+			"oci://ghcr.io/github/test@sha256:arm64",
+		}, paths)
+	})
+
+	t.Run("--platform filters the index to matching manifests", func(t *testing.T) {
+		opts := &Options{
+			Platforms: []string{"linux/arm64"},
+			OCIClient: indexMockClient{
+				isIndex: true,
+				manifests: []platformManifest{
+					{Platform: "linux/amd64", Ref: "oci://ghcr.io/github/test@sha256:amd64"},
+					{Platform: "linux/arm64", Ref: "oci://ghcr.io/github/test@sha256:arm64"},
+				},
+			},
+		}
+		paths, err := expandOCIReferences(opts, []string{"oci://ghcr.io/github/test"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"oci://ghcr.io/github/test@sha256:arm64"}, paths)
+	})
+
+	t.Run("no matching platform is an error", func(t *testing.T) {
+		opts := &Options{
+			Platforms: []string{"windows/amd64"},
+			OCIClient: indexMockClient{
+				isIndex: true,
+				manifests: []platformManifest{
+					{Platform: "linux/amd64", Ref: "oci://ghcr.io/github/test@sha256:amd64"},
+				},
+			},
+		}
+		_, err := expandOCIReferences(opts, []string{"oci://ghcr.io/github/test"})
+		require.Error(t, err)
+	})
+}
+
+func TestResolveReferrerBundles(t *testing.T) {
+	t.Run("errors when the client has no referrers configured", func(t *testing.T) {
+		opts := &Options{OCIClient: oci.MockClient{}}
+		_, err := resolveReferrerBundles(opts, "oci://ghcr.io/github/test")
+		require.Error(t, err)
+	})
+
+	t.Run("errors when no referrers are found", func(t *testing.T) {
+		opts := &Options{OCIClient: indexMockClient{refs: []string{}}}
+		_, err := resolveReferrerBundles(opts, "oci://ghcr.io/github/test")
+		require.Error(t, err)
+	})
+
+	t.Run("returns the discovered bundle references", func(t *testing.T) {
+		opts := &Options{OCIClient: indexMockClient{refs: []string{"oci://ghcr.io/github/test:sha256-abc.att"}}}
+		refs, err := resolveReferrerBundles(opts, "oci://ghcr.io/github/test")
+		require.NoError(t, err)
+		require.Equal(t, []string{"oci://ghcr.io/github/test:sha256-abc.att"}, refs)
+	})
+}
+
+func TestFetchReferrerAttestations(t *testing.T) {
+	bundleBytes, err := os.ReadFile(bundlePath)
+	require.NoError(t, err)
+
+	t.Run("errors when the client can't fetch referrer bundles", func(t *testing.T) {
+		opts := &Options{OCIClient: oci.MockClient{}}
+		_, err := fetchReferrerAttestations(opts, "oci://ghcr.io/github/test:sha256-abc.att")
+		require.Error(t, err)
+	})
+
+	t.Run("fetches and parses the bundle discovered via the referrers API", func(t *testing.T) {
+		ref := "oci://ghcr.io/github/test:sha256-abc.att"
+		opts := &Options{OCIClient: indexMockClient{
+			bundleBytes: map[string][]byte{ref: bundleBytes},
+		}}
+
+		attestations, err := fetchReferrerAttestations(opts, ref)
+		require.NoError(t, err)
+		require.NotEmpty(t, attestations)
+	})
+}
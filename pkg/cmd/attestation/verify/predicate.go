@@ -0,0 +1,152 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/io"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/verification"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+)
+
+// Predicate type URIs recognized out of the box. Callers may still require
+// an arbitrary URI via --predicate-type; one not listed here is checked for
+// presence only, with no predicate-specific policy enforcement.
+const (
+	SLSAPredicateTypeV02   = "https://slsa.dev/provenance/v0.2"
+	SPDXPredicateType      = "https://spdx.dev/Document"
+	CycloneDXPredicateType = "https://cyclonedx.org/bom"
+	VSAPredicateType       = "https://in-toto.io/attestation/vsa/v0.2"
+)
+
+// ErrNoMatchingPredicateType is returned when none of the verified
+// attestations carry a predicate type requested via --predicate-type.
+var ErrNoMatchingPredicateType = errors.New("verified attestations do not contain a matching predicate type")
+
+// ErrPredicateTypePolicyFailed is returned when an attestation carries a
+// predicate type requested via --predicate-type, but fails that predicate
+// type's policy (e.g. a VSA whose verificationResult isn't PASSED). This is
+// distinct from ErrNoMatchingPredicateType, which means no attestation had
+// the requested type at all.
+var ErrPredicateTypePolicyFailed = errors.New("an attestation matched a requested predicate type but failed its policy")
+
+// PredicateVerifier enforces predicate-specific policy against an in-toto
+// statement that has already passed signature verification.
+type PredicateVerifier func(statement *in_toto.Statement) error
+
+// VerifiedPredicateResult pairs a verified attestation with the set of
+// --predicate-type values it satisfied, so --format json output can show
+// which predicate policies matched which attestation.
+type VerifiedPredicateResult struct {
+	*verification.AttestationProcessingResult
+	MatchedPredicateTypes []string `json:"matchedPredicateTypes,omitempty"`
+}
+
+// annotatePredicateTypes wraps results without enforcing any predicate
+// policy, for the default (no --predicate-type) path.
+func annotatePredicateTypes(results []*verification.AttestationProcessingResult) []*VerifiedPredicateResult {
+	annotated := make([]*VerifiedPredicateResult, len(results))
+	for i, r := range results {
+		annotated[i] = &VerifiedPredicateResult{AttestationProcessingResult: r}
+	}
+	return annotated
+}
+
+// predicateVerifiers is the built-in registry of predicate type URIs to the
+// policy enforced for that type. Additional predicate types requested via
+// --predicate-type that aren't in this registry are still matched by URI,
+// just without extra policy enforcement.
+var predicateVerifiers = map[string]PredicateVerifier{
+	SLSAPredicateType:      verifySLSAProvenance,
+	SLSAPredicateTypeV02:   verifySLSAProvenance,
+	SPDXPredicateType:      verifySPDXPredicate,
+	CycloneDXPredicateType: verifyCycloneDXPredicate,
+	VSAPredicateType:       verifyVSAPredicate,
+}
+
+func verifySLSAProvenance(statement *in_toto.Statement) error {
+	if statement.PredicateType != SLSAPredicateType && statement.PredicateType != SLSAPredicateTypeV02 {
+		return ErrNoMatchingSLSAPredicate
+	}
+	return nil
+}
+
+func verifySPDXPredicate(statement *in_toto.Statement) error {
+	predicate, ok := statement.Predicate.(map[string]any)
+	if !ok {
+		return fmt.Errorf("could not parse SPDX predicate")
+	}
+	if _, ok := predicate["spdxVersion"]; !ok {
+		return fmt.Errorf("SPDX predicate is missing required field spdxVersion")
+	}
+	return nil
+}
+
+func verifyCycloneDXPredicate(statement *in_toto.Statement) error {
+	predicate, ok := statement.Predicate.(map[string]any)
+	if !ok {
+		return fmt.Errorf("could not parse CycloneDX predicate")
+	}
+	if _, ok := predicate["bomFormat"]; !ok {
+		return fmt.Errorf("CycloneDX predicate is missing required field bomFormat")
+	}
+	return nil
+}
+
+func verifyVSAPredicate(statement *in_toto.Statement) error {
+	predicate, ok := statement.Predicate.(map[string]any)
+	if !ok {
+		return fmt.Errorf("could not parse VSA predicate")
+	}
+	result, ok := predicate["verificationResult"]
+	if !ok {
+		return fmt.Errorf("VSA predicate is missing required field verificationResult")
+	}
+	if result != "PASSED" {
+		return fmt.Errorf("VSA verificationResult is %q, expected PASSED", result)
+	}
+	return nil
+}
+
+// verifyPredicateTypes checks every requested predicate type against the
+// verified attestations, running that predicate type's registered
+// PredicateVerifier against every matching statement, and annotates each
+// result with the requested predicate types it satisfied. It returns
+// ErrNoMatchingPredicateType if a requested type matches no attestation at
+// all, or ErrPredicateTypePolicyFailed if a requested type matches an
+// attestation that then fails that type's policy.
+func verifyPredicateTypes(logger *io.Handler, results []*verification.AttestationProcessingResult, predicateTypes []string) ([]*VerifiedPredicateResult, error) {
+	annotated := annotatePredicateTypes(results)
+
+	for _, predicateType := range predicateTypes {
+		verifier, ok := predicateVerifiers[predicateType]
+		matched := false
+		presentButFailed := false
+		for _, r := range annotated {
+			if r.VerificationResult == nil || r.VerificationResult.Statement == nil {
+				continue
+			}
+			statement := r.VerificationResult.Statement
+			if statement.PredicateType != predicateType {
+				continue
+			}
+			if ok {
+				if err := verifier(statement); err != nil {
+					logger.Printf("predicate type %s did not satisfy policy: %v\n", predicateType, err)
+					presentButFailed = true
+					continue
+				}
+			}
+			r.MatchedPredicateTypes = append(r.MatchedPredicateTypes, predicateType)
+			matched = true
+		}
+		if !matched {
+			if presentButFailed {
+				return nil, fmt.Errorf("%w: %s", ErrPredicateTypePolicyFailed, predicateType)
+			}
+			return nil, fmt.Errorf("%w: %s", ErrNoMatchingPredicateType, predicateType)
+		}
+	}
+	return annotated, nil
+}
@@ -0,0 +1,347 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/api"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact/oci"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/auth"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/io"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/verification"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+// GitHubOIDCIssuer is the default OIDC issuer used to verify attestations
+// signed through GitHub Actions.
+const GitHubOIDCIssuer = "https://token.actions.githubusercontent.com"
+
+// SLSAPredicateType is the predicate type of the SLSA provenance attestations
+// produced by the default `gh attestation` signing flows.
+const SLSAPredicateType = "https://slsa.dev/provenance/v1"
+
+// ErrNoMatchingSLSAPredicate is returned when none of the verified
+// attestations carry the expected SLSA provenance predicate type.
+var ErrNoMatchingSLSAPredicate = errors.New("verified attestations do not contain a matching SLSA predicate")
+
+const DigestAlgorithmSha256 = "sha256"
+
+var defaultDigestAlgorithm = DigestAlgorithmSha256
+
+var validDigestAlgorithms = []string{DigestAlgorithmSha256, "sha384", "sha512"}
+
+// Options captures the inputs needed to verify one or more artifacts against
+// their attestations.
+type Options struct {
+	AllPlatforms         bool
+	ArtifactPath         string
+	ArtifactPaths        []string
+	BundlePath           string
+	CustomTrustedRoot    string
+	DenySelfHostedRunner bool
+	DigestAlgorithm      string
+	Jobs                 int
+	Limit                int
+	NoPublicGood         bool
+	OIDCIssuer           string
+	OIDCIssuerRegex      string
+	Offline              bool
+	Owner                string
+	Platforms            []string
+	PredicateTypes       []string
+	Referrers            bool
+	Repo                 string
+	SAN                  string
+	SANRegex             string
+	TrustedRoot          string
+	TUFCacheDir          string
+
+	APIClient api.Client
+	Logger    *io.Handler
+	OCIClient oci.Client
+
+	exporter cmdutil.Exporter
+}
+
+// artifactPaths resolves the set of artifacts this run should verify.
+// ArtifactPaths takes precedence when populated by the CLI's glob/directory/
+// filelist expansion; ArtifactPath remains supported for single-artifact
+// callers, including existing tests that construct an Options directly.
+func (o *Options) artifactPaths() ([]string, error) {
+	if len(o.ArtifactPaths) > 0 {
+		return o.ArtifactPaths, nil
+	}
+	if o.ArtifactPath != "" {
+		return []string{o.ArtifactPath}, nil
+	}
+	return nil, errors.New("no artifact path provided")
+}
+
+// NewVerifyCmd creates the `gh attestation verify` command.
+func NewVerifyCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "verify [<file-path>... | oci://<image-uri> | @filelist.txt] [--owner | --repo]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Verify an artifact's supply chain provenance attestations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, err := resolveArtifactPaths(args)
+			if err != nil {
+				return err
+			}
+			opts.ArtifactPaths = paths
+			if len(paths) == 1 {
+				opts.ArtifactPath = paths[0]
+			}
+
+			if opts.Offline {
+				if opts.Owner != "" || opts.Repo != "" {
+					return cmdutil.FlagErrorf("`--owner` and `--repo` cannot be used with `--offline`, which never contacts the GitHub API")
+				}
+				if opts.BundlePath == "" {
+					return cmdutil.FlagErrorf("`--bundle` is required when `--offline` is set")
+				}
+				if opts.TrustedRoot == "" && opts.CustomTrustedRoot == "" && opts.TUFCacheDir == "" {
+					return cmdutil.FlagErrorf("one of `--trusted-root`, `--custom-trusted-root`, or `--tuf-cache-dir` is required when `--offline` is set")
+				}
+			} else if opts.Owner == "" && opts.Repo == "" {
+				return cmdutil.FlagErrorf("at least one of `--owner` or `--repo` is required")
+			}
+			if opts.Owner != "" && opts.Repo != "" {
+				return cmdutil.FlagErrorf("only one of `--owner` or `--repo` may be specified")
+			}
+
+			if opts.SAN != "" && opts.SANRegex != "" {
+				return cmdutil.FlagErrorf("only one of `--cert-identity` or `--cert-identity-regex` may be specified")
+			}
+
+			if cmd.Flags().Changed("cert-oidc-issuer") && opts.OIDCIssuerRegex != "" {
+				return cmdutil.FlagErrorf("only one of `--cert-oidc-issuer` or `--cert-oidc-issuer-regex` may be specified")
+			}
+
+			if opts.OIDCIssuerRegex != "" && !cmd.Flags().Changed("cert-oidc-issuer") {
+				opts.OIDCIssuer = ""
+			}
+
+			if opts.Limit < 1 || opts.Limit > 1000 {
+				return cmdutil.FlagErrorf("`--limit` must be between 1 and 1000")
+			}
+
+			if !contains(validDigestAlgorithms, opts.DigestAlgorithm) {
+				return cmdutil.FlagErrorf("`--digest-alg` must be one of %v", validDigestAlgorithms)
+			}
+
+			if opts.BundlePath == "" && opts.SAN == "" && opts.SANRegex == "" {
+				opts.SANRegex = fmt.Sprintf("^https://github.com/%s/", opts.Owner)
+			}
+
+			logger := io.NewHandler(f.IOStreams)
+			opts.Logger = logger
+
+			if opts.Offline {
+				// Offline verification must never construct a client capable of
+				// reaching the GitHub API, Fulcio, Rekor, or the TUF mirror.
+				opts.OCIClient = oci.NewLiveClient()
+			} else {
+				httpClient, err := f.HttpClient()
+				if err != nil {
+					return err
+				}
+				hostname, _ := auth.DefaultHost()
+				opts.APIClient = api.NewLiveClient(httpClient, hostname, logger)
+				opts.OCIClient = oci.NewLiveClient()
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return runVerify(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.AllPlatforms, "all-platforms", false, "Verify every child manifest of an OCI image index or Docker manifest list")
+	cmd.Flags().StringVarP(&opts.BundlePath, "bundle", "b", "", "Path to a sigstore bundle on disk, likely obtained via `gh attestation download`")
+	cmd.Flags().StringVar(&opts.CustomTrustedRoot, "custom-trusted-root", "", "Path to a custom trusted_root.jsonl file used for verifying artifacts in an air-gapped environment")
+	cmd.Flags().BoolVar(&opts.DenySelfHostedRunner, "deny-self-hosted-runners", false, "Fail verification for attestations signed in a self-hosted runner")
+	cmd.Flags().StringVar(&opts.DigestAlgorithm, "digest-alg", defaultDigestAlgorithm, "The algorithm used to compute a digest of the artifact: {sha256|sha384|sha512}")
+	cmd.Flags().IntVar(&opts.Jobs, "jobs", 4, "Number of artifacts to verify concurrently")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "L", 30, "Maximum number of attestations to fetch")
+	cmd.Flags().BoolVar(&opts.NoPublicGood, "no-public-good", false, "Do not verify attestations signed with Sigstore's public good instance")
+	cmd.Flags().StringVar(&opts.OIDCIssuer, "cert-oidc-issuer", GitHubOIDCIssuer, "Issuer of the OIDC token used for the signing certificate")
+	cmd.Flags().StringVar(&opts.OIDCIssuerRegex, "cert-oidc-issuer-regex", "", "Regex for verifying the issuer of the OIDC token used for the signing certificate")
+	cmd.Flags().BoolVar(&opts.Offline, "offline", false, "Verify using only a local bundle and trusted root, without contacting the GitHub API, Fulcio, Rekor, or TUF")
+	cmd.Flags().StringVarP(&opts.Owner, "owner", "o", "", "GitHub organization to scope attestation lookup by")
+	cmd.Flags().StringSliceVar(&opts.Platforms, "platform", nil, "Only verify child manifests of an OCI image index matching this platform, e.g. linux/amd64 (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&opts.PredicateTypes, "predicate-type", nil, "Require the verified attestations to include each of the provided predicate types, e.g. https://spdx.dev/Document (can be specified multiple times)")
+	cmd.Flags().BoolVar(&opts.Referrers, "referrers", false, "Discover attestations for an oci:// reference using the OCI 1.1 Referrers API instead of requiring --bundle")
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository name in the format <owner>/<repo>")
+	cmd.Flags().StringVarP(&opts.SAN, "cert-identity", "", "", "The SAN that must be present in the signing certificate")
+	cmd.Flags().StringVar(&opts.SANRegex, "cert-identity-regex", "", "Regex for verifying the SAN that must be present in the signing certificate")
+	cmd.Flags().StringVar(&opts.TrustedRoot, "trusted-root", "", "Path to a trusted_root.json file on disk, e.g. the one written by `gh attestation trust-root refresh`")
+	cmd.Flags().StringVar(&opts.TUFCacheDir, "tuf-cache-dir", "", "Directory containing a cached copy of the Sigstore TUF root, e.g. one written by `gh attestation trust-root refresh`; one of --trusted-root, --custom-trusted-root, or --tuf-cache-dir is required with --offline")
+
+	cmdutil.AddFormatFlags(cmd, &opts.exporter)
+
+	return cmd
+}
+
+// runVerify resolves the set of artifacts to verify and, for a single
+// artifact, preserves the historical behavior of verifying it directly. For
+// a batch of artifacts it fans the work out across a bounded worker pool,
+// keyed by subject digest; verification of the remaining artifacts in the
+// batch continues even if one fails.
+func runVerify(opts *Options) error {
+	paths, err := opts.artifactPaths()
+	if err != nil {
+		return err
+	}
+
+	paths, err = expandOCIReferences(opts, paths)
+	if err != nil {
+		return err
+	}
+
+	if len(paths) == 1 {
+		digest, results, err := verifyArtifact(opts, paths[0])
+		if err != nil {
+			return err
+		}
+		if opts.exporter != nil {
+			combined := map[string][]*VerifiedPredicateResult{digest: results}
+			return opts.exporter.Write(opts.Logger.IO, combined)
+		}
+		opts.Logger.Printf("Verified %d attestation(s) for %s (digest %s)\n", len(results), paths[0], digest)
+		return nil
+	}
+
+	return runBatchVerify(opts, paths)
+}
+
+// verifyArtifact digests a single artifact, fetches its attestations, and
+// verifies them against the caller's identity and predicate policy. Each
+// returned result is annotated with the --predicate-type values it matched,
+// if any were requested.
+func verifyArtifact(opts *Options, path string) (string, []*VerifiedPredicateResult, error) {
+	artifactDigest, err := artifact.DigestPath(path, opts.DigestAlgorithm, opts.OCIClient)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to digest artifact: %w", err)
+	}
+
+	var attestations []*api.Attestation
+	if opts.Referrers && strings.HasPrefix(path, "oci://") {
+		bundleRefs, err := resolveReferrerBundles(opts, path)
+		if err != nil {
+			return artifactDigest, nil, err
+		}
+		for _, bundleRef := range bundleRefs {
+			bundleAttestations, err := fetchReferrerAttestations(opts, bundleRef)
+			if err != nil {
+				return artifactDigest, nil, err
+			}
+			attestations = append(attestations, bundleAttestations...)
+		}
+	} else if opts.BundlePath != "" {
+		attestations, err = api.AttestationsFromBundlePath(opts.BundlePath)
+		if err != nil {
+			return artifactDigest, nil, fmt.Errorf("failed to load attestations from bundle: %w", err)
+		}
+	} else {
+		if opts.Offline {
+			return artifactDigest, nil, errors.New("--offline requires --bundle; fetching attestations from the GitHub API is not permitted")
+		}
+		if opts.APIClient == nil {
+			return artifactDigest, nil, errors.New("an API client is required to fetch attestations when no --bundle is provided")
+		}
+		owner := opts.Owner
+		if owner == "" {
+			owner = ownerFromRepo(opts.Repo)
+		}
+		attestations, err = opts.APIClient.GetByDigest(owner, opts.Repo, artifactDigest, opts.Limit)
+		if err != nil {
+			return artifactDigest, nil, fmt.Errorf("failed to fetch attestations for subject: %w", err)
+		}
+	}
+
+	trustedRootPath := opts.TrustedRoot
+	if trustedRootPath == "" {
+		trustedRootPath = opts.CustomTrustedRoot
+	}
+	if trustedRootPath == "" && opts.TUFCacheDir != "" {
+		// `gh attestation trust-root refresh` caches the TUF trust root as
+		// trusted_root.json in --tuf-cache-dir; accept that same directory
+		// here so users can hand it straight to --offline as advertised.
+		trustedRootPath = filepath.Join(opts.TUFCacheDir, "trusted_root.json")
+	}
+
+	policy, err := verification.BuildPolicy(verification.PolicyOptions{
+		OIDCIssuer:           opts.OIDCIssuer,
+		OIDCIssuerRegex:      opts.OIDCIssuerRegex,
+		SAN:                  opts.SAN,
+		SANRegex:             opts.SANRegex,
+		DenySelfHostedRunner: opts.DenySelfHostedRunner,
+		NoPublicGood:         opts.NoPublicGood,
+		CustomTrustedRoot:    opts.CustomTrustedRoot,
+		Offline:              opts.Offline,
+		TrustedRootPath:      trustedRootPath,
+		TUFCacheDir:          opts.TUFCacheDir,
+	})
+	if err != nil {
+		return artifactDigest, nil, fmt.Errorf("failed to build verification policy: %w", err)
+	}
+
+	results, err := verification.VerifyAttestations(attestations, policy)
+	if err != nil {
+		return artifactDigest, nil, err
+	}
+
+	if len(opts.PredicateTypes) > 0 {
+		annotated, err := verifyPredicateTypes(opts.Logger, results, opts.PredicateTypes)
+		if err != nil {
+			return artifactDigest, nil, err
+		}
+		return artifactDigest, annotated, nil
+	}
+
+	if err := verifySLSAPredicateType(opts.Logger, results); err != nil {
+		return artifactDigest, nil, err
+	}
+
+	return artifactDigest, annotatePredicateTypes(results), nil
+}
+
+// verifySLSAPredicateType ensures at least one verified attestation carries
+// the expected SLSA provenance predicate type.
+func verifySLSAPredicateType(logger *io.Handler, results []*verification.AttestationProcessingResult) error {
+	for _, r := range results {
+		if r.VerificationResult != nil && r.VerificationResult.Statement != nil {
+			if r.VerificationResult.Statement.PredicateType == SLSAPredicateType {
+				return nil
+			}
+		}
+	}
+	return ErrNoMatchingSLSAPredicate
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, v := range haystack {
+		if v == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func ownerFromRepo(repo string) string {
+	for i := 0; i < len(repo); i++ {
+		if repo[i] == '/' {
+			return repo[:i]
+		}
+	}
+	return repo
+}
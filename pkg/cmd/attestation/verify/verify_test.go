@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 	"testing"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/in-toto/in-toto-golang/in_toto"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/sigstore/sigstore-go/pkg/verify"
 
 	"github.com/stretchr/testify/require"
@@ -61,6 +63,7 @@ func TestNewVerifyCmd(t *testing.T) {
 				ArtifactPath:    test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0.tgz"),
 				BundlePath:      test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0-bundle.json"),
 				DigestAlgorithm: "sha384",
+				Jobs:            4,
 				Limit:           30,
 				OIDCIssuer:      GitHubOIDCIssuer,
 				Owner:           "sigstore",
@@ -74,6 +77,7 @@ func TestNewVerifyCmd(t *testing.T) {
 				ArtifactPath:    test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0.tgz"),
 				BundlePath:      test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0-bundle.json"),
 				DigestAlgorithm: "sha256",
+				Jobs:            4,
 				Limit:           30,
 				OIDCIssuer:      GitHubOIDCIssuer,
 				Owner:           "sigstore",
@@ -88,6 +92,7 @@ func TestNewVerifyCmd(t *testing.T) {
 				ArtifactPath:    test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0.tgz"),
 				BundlePath:      test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0-bundle.json"),
 				DigestAlgorithm: "sha512",
+				Jobs:            4,
 				Limit:           30,
 				OIDCIssuer:      GitHubOIDCIssuer,
 				Owner:           "sigstore",
@@ -103,6 +108,7 @@ func TestNewVerifyCmd(t *testing.T) {
 				DigestAlgorithm: "sha256",
 				OIDCIssuer:      GitHubOIDCIssuer,
 				Owner:           "sigstore",
+				Jobs:            4,
 				Limit:           30,
 				SANRegex:        "^https://github.com/sigstore/",
 			},
@@ -117,6 +123,7 @@ func TestNewVerifyCmd(t *testing.T) {
 				OIDCIssuer:      GitHubOIDCIssuer,
 				Owner:           "sigstore",
 				Repo:            "sigstore/sigstore-js",
+				Jobs:            4,
 				Limit:           30,
 			},
 			wantsErr: true,
@@ -127,6 +134,7 @@ func TestNewVerifyCmd(t *testing.T) {
 			wants: Options{
 				ArtifactPath:    artifactPath,
 				DigestAlgorithm: "sha256",
+				Jobs:            4,
 				Limit:           30,
 				OIDCIssuer:      GitHubOIDCIssuer,
 				Owner:           "sigstore",
@@ -140,6 +148,7 @@ func TestNewVerifyCmd(t *testing.T) {
 			wants: Options{
 				ArtifactPath:    artifactPath,
 				DigestAlgorithm: "sha256",
+				Jobs:            4,
 				OIDCIssuer:      GitHubOIDCIssuer,
 				Owner:           "sigstore",
 				Limit:           101,
@@ -166,6 +175,7 @@ func TestNewVerifyCmd(t *testing.T) {
 			wants: Options{
 				ArtifactPath:    artifactPath,
 				DigestAlgorithm: "sha256",
+				Jobs:            4,
 				Limit:           30,
 				OIDCIssuer:      GitHubOIDCIssuer,
 				Owner:           "sigstore",
@@ -174,6 +184,144 @@ func TestNewVerifyCmd(t *testing.T) {
 			},
 			wantsErr: true,
 		},
+		{
+			name: "Has both cert-oidc-issuer and cert-oidc-issuer-regex flags",
+			cli:  fmt.Sprintf("%s --owner sigstore --cert-oidc-issuer https://token.actions.githubusercontent.com --cert-oidc-issuer-regex ^https://token\\.actions\\.githubusercontent\\.com/.+", artifactPath),
+			wants: Options{
+				ArtifactPath:    artifactPath,
+				DigestAlgorithm: "sha256",
+				Jobs:            4,
+				Limit:           30,
+				OIDCIssuer:      GitHubOIDCIssuer,
+				OIDCIssuerRegex: `^https://token\.actions\.githubusercontent\.com/.+`,
+				Owner:           "sigstore",
+			},
+			wantsErr: true,
+		},
+		{
+			name: "Uses cert-oidc-issuer-regex flag",
+			cli:  fmt.Sprintf("%s --bundle %s --owner sigstore --cert-oidc-issuer-regex ^https://token\\.actions\\.githubusercontent\\.com/.+", artifactPath, bundlePath),
+			wants: Options{
+				ArtifactPath:    test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0.tgz"),
+				BundlePath:      test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0-bundle.json"),
+				DigestAlgorithm: "sha256",
+				Jobs:            4,
+				Limit:           30,
+				OIDCIssuerRegex: `^https://token\.actions\.githubusercontent\.com/.+`,
+				Owner:           "sigstore",
+				SANRegex:        "^https://github.com/sigstore/",
+			},
+			wantsErr: false,
+		},
+		{
+			name: "Uses custom jobs flag",
+			cli:  fmt.Sprintf("%s --bundle %s --owner sigstore --jobs 8", artifactPath, bundlePath),
+			wants: Options{
+				ArtifactPath:    test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0.tgz"),
+				BundlePath:      test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0-bundle.json"),
+				DigestAlgorithm: "sha256",
+				Jobs:            8,
+				Limit:           30,
+				OIDCIssuer:      GitHubOIDCIssuer,
+				Owner:           "sigstore",
+				SANRegex:        "^https://github.com/sigstore/",
+			},
+			wantsErr: false,
+		},
+		{
+			name: "Accepts multiple artifact path arguments",
+			cli:  fmt.Sprintf("%s %s --owner sigstore --bundle %s", artifactPath, artifactPath, bundlePath),
+			wants: Options{
+				ArtifactPaths:   []string{artifactPath, artifactPath},
+				BundlePath:      bundlePath,
+				DigestAlgorithm: "sha256",
+				Jobs:            4,
+				Limit:           30,
+				OIDCIssuer:      GitHubOIDCIssuer,
+				Owner:           "sigstore",
+				SANRegex:        "^https://github.com/sigstore/",
+			},
+			wantsErr: false,
+		},
+		{
+			name: "Uses repeatable predicate-type flag",
+			cli:  fmt.Sprintf("%s --bundle %s --owner sigstore --predicate-type %s --predicate-type %s", artifactPath, bundlePath, SPDXPredicateType, CycloneDXPredicateType),
+			wants: Options{
+				ArtifactPath:    test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0.tgz"),
+				BundlePath:      test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0-bundle.json"),
+				DigestAlgorithm: "sha256",
+				Jobs:            4,
+				Limit:           30,
+				OIDCIssuer:      GitHubOIDCIssuer,
+				Owner:           "sigstore",
+				PredicateTypes:  []string{SPDXPredicateType, CycloneDXPredicateType},
+				SANRegex:        "^https://github.com/sigstore/",
+			},
+			wantsErr: false,
+		},
+		{
+			name:     "Offline mode requires a bundle",
+			cli:      fmt.Sprintf("%s --offline --trusted-root ../test/data/trusted_root.json", artifactPath),
+			wantsErr: true,
+		},
+		{
+			name:     "Offline mode requires a trusted root",
+			cli:      fmt.Sprintf("%s --offline --bundle %s", artifactPath, bundlePath),
+			wantsErr: true,
+		},
+		{
+			name: "Offline mode accepts a tuf-cache-dir in place of a trusted root",
+			cli:  fmt.Sprintf("%s --offline --bundle %s --tuf-cache-dir ../test/data", artifactPath, bundlePath),
+			wants: Options{
+				ArtifactPath:    artifactPath,
+				BundlePath:      bundlePath,
+				DigestAlgorithm: "sha256",
+				Jobs:            4,
+				Limit:           30,
+				OIDCIssuer:      GitHubOIDCIssuer,
+				Offline:         true,
+				TUFCacheDir:     "../test/data",
+			},
+			wantsErr: false,
+		},
+		{
+			name:     "Offline mode rejects --owner",
+			cli:      fmt.Sprintf("%s --offline --owner sigstore --bundle %s --trusted-root ../test/data/trusted_root.json", artifactPath, bundlePath),
+			wantsErr: true,
+		},
+		{
+			name: "Uses offline mode with a bundle and trusted root",
+			cli:  fmt.Sprintf("%s --offline --bundle %s --trusted-root ../test/data/trusted_root.json", artifactPath, bundlePath),
+			wants: Options{
+				ArtifactPath:    artifactPath,
+				BundlePath:      bundlePath,
+				DigestAlgorithm: "sha256",
+				Jobs:            4,
+				Limit:           30,
+				OIDCIssuer:      GitHubOIDCIssuer,
+				Offline:         true,
+				TrustedRoot:     "../test/data/trusted_root.json",
+			},
+			wantsErr: false,
+		},
+		{
+			name: "Uses platform and all-platforms flags",
+			cli:  fmt.Sprintf("%s --bundle %s --owner sigstore --platform linux/amd64 --platform linux/arm64 --all-platforms --referrers", artifactPath, bundlePath),
+			wants: Options{
+				AllPlatforms:    true,
+				ArtifactPath:    test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0.tgz"),
+				BundlePath:      test.NormalizeRelativePath("../test/data/sigstore-js-2.1.0-bundle.json"),
+				DigestAlgorithm: "sha256",
+				Jobs:            4,
+				Limit:           30,
+				OIDCIssuer:      GitHubOIDCIssuer,
+				Owner:           "sigstore",
+				Platforms:       []string{"linux/amd64", "linux/arm64"},
+				Referrers:       true,
+				SANRegex:        "^https://github.com/sigstore/",
+			},
+			wantsErr: false,
+		},
 		{
 			name: "Prints output in JSON format",
 			cli:  fmt.Sprintf("%s --bundle %s --owner sigstore --format json", artifactPath, bundlePath),
@@ -181,6 +329,7 @@ func TestNewVerifyCmd(t *testing.T) {
 				ArtifactPath:    artifactPath,
 				BundlePath:      bundlePath,
 				DigestAlgorithm: "sha256",
+				Jobs:            4,
 				Limit:           30,
 				OIDCIssuer:      GitHubOIDCIssuer,
 				Owner:           "sigstore",
@@ -210,15 +359,27 @@ func TestNewVerifyCmd(t *testing.T) {
 			}
 			assert.NoError(t, err)
 
+			assert.Equal(t, tc.wants.AllPlatforms, opts.AllPlatforms)
 			assert.Equal(t, tc.wants.ArtifactPath, opts.ArtifactPath)
+			if tc.wants.ArtifactPaths != nil {
+				assert.Equal(t, tc.wants.ArtifactPaths, opts.ArtifactPaths)
+			}
 			assert.Equal(t, tc.wants.BundlePath, opts.BundlePath)
 			assert.Equal(t, tc.wants.CustomTrustedRoot, opts.CustomTrustedRoot)
 			assert.Equal(t, tc.wants.DenySelfHostedRunner, opts.DenySelfHostedRunner)
 			assert.Equal(t, tc.wants.DigestAlgorithm, opts.DigestAlgorithm)
+			assert.Equal(t, tc.wants.Jobs, opts.Jobs)
 			assert.Equal(t, tc.wants.Limit, opts.Limit)
 			assert.Equal(t, tc.wants.NoPublicGood, opts.NoPublicGood)
 			assert.Equal(t, tc.wants.OIDCIssuer, opts.OIDCIssuer)
+			assert.Equal(t, tc.wants.OIDCIssuerRegex, opts.OIDCIssuerRegex)
+			assert.Equal(t, tc.wants.Offline, opts.Offline)
+			assert.Equal(t, tc.wants.TrustedRoot, opts.TrustedRoot)
+			assert.Equal(t, tc.wants.TUFCacheDir, opts.TUFCacheDir)
 			assert.Equal(t, tc.wants.Owner, opts.Owner)
+			assert.Equal(t, tc.wants.Platforms, opts.Platforms)
+			assert.Equal(t, tc.wants.PredicateTypes, opts.PredicateTypes)
+			assert.Equal(t, tc.wants.Referrers, opts.Referrers)
 			assert.Equal(t, tc.wants.Repo, opts.Repo)
 			assert.Equal(t, tc.wants.SAN, opts.SAN)
 			assert.Equal(t, tc.wants.SANRegex, opts.SANRegex)
@@ -241,14 +402,20 @@ func TestJSONOutput(t *testing.T) {
 		OCIClient:       oci.MockClient{},
 		OIDCIssuer:      GitHubOIDCIssuer,
 		Owner:           "sigstore",
+		PredicateTypes:  []string{SLSAPredicateType},
 		SANRegex:        "^https://github.com/sigstore/",
 		exporter:        cmdutil.NewJSONExporter(),
 	}
 	require.Nil(t, runVerify(&opts))
 
-	var target []*verification.AttestationProcessingResult
+	var target map[string][]*VerifiedPredicateResult
 	err := json.Unmarshal(out.Bytes(), &target)
 	require.NoError(t, err)
+	require.Len(t, target, 1)
+	for _, results := range target {
+		require.NotEmpty(t, results)
+		require.Contains(t, results[0].MatchedPredicateTypes, SLSAPredicateType)
+	}
 }
 
 func TestRunVerify(t *testing.T) {
@@ -280,6 +447,29 @@ func TestRunVerify(t *testing.T) {
 		require.ErrorContains(t, err, "failed to digest artifact")
 	})
 
+	t.Run("with referrers enabled", func(t *testing.T) {
+		bundleBytes, err := os.ReadFile(bundlePath)
+		require.NoError(t, err)
+
+		ref := "oci://ghcr.io/github/test:sha256-abc.att"
+		hash, err := v1.NewHash("sha256:" + strings.Repeat("a", 64))
+		require.NoError(t, err)
+
+		opts := publicGoodOpts
+		opts.ArtifactPath = "oci://ghcr.io/github/test"
+		opts.BundlePath = ""
+		opts.Referrers = true
+		opts.OCIClient = indexMockClient{
+			MockClient: oci.MockClient{
+				OnGetImageDigest: func(imgName string) (*v1.Hash, error) { return &hash, nil },
+			},
+			refs:        []string{ref},
+			bundleBytes: map[string][]byte{ref: bundleBytes},
+		}
+
+		require.Nil(t, runVerify(&opts))
+	})
+
 	t.Run("with missing artifact path", func(t *testing.T) {
 		opts := publicGoodOpts
 		opts.ArtifactPath = "../test/data/non-existent-artifact.zip"
@@ -375,12 +565,100 @@ func TestRunVerify(t *testing.T) {
 		require.Error(t, runVerify(&opts))
 	})
 
+	t.Run("with OIDC issuer regex enforcement", func(t *testing.T) {
+		opts := publicGoodOpts
+		opts.OIDCIssuer = ""
+		opts.OIDCIssuerRegex = "^https://token\\.actions\\.githubusercontent\\.com/.+"
+
+		require.Nil(t, runVerify(&opts))
+	})
+
+	t.Run("with no matching OIDC issuer regex", func(t *testing.T) {
+		opts := publicGoodOpts
+		opts.OIDCIssuer = ""
+		opts.OIDCIssuerRegex = "^https://not-a-real-issuer/.+"
+
+		require.Error(t, runVerify(&opts))
+	})
+
 	t.Run("with missing API client", func(t *testing.T) {
 		customOpts := publicGoodOpts
 		customOpts.APIClient = nil
 		customOpts.BundlePath = ""
 		require.Error(t, runVerify(&customOpts))
 	})
+
+	t.Run("with a batch of valid artifacts", func(t *testing.T) {
+		opts := publicGoodOpts
+		opts.ArtifactPath = ""
+		opts.ArtifactPaths = []string{artifactPath, artifactPath}
+		opts.Jobs = 2
+
+		require.Nil(t, runVerify(&opts))
+	})
+
+	t.Run("with a batch of valid artifacts and an exporter", func(t *testing.T) {
+		testIO, _, out, _ := iostreams.Test()
+		opts := publicGoodOpts
+		opts.Logger = io.NewHandler(testIO)
+		opts.ArtifactPath = ""
+		opts.ArtifactPaths = []string{artifactPath, artifactPath}
+		opts.Jobs = 2
+		opts.exporter = cmdutil.NewJSONExporter()
+
+		require.Nil(t, runVerify(&opts))
+
+		var target map[string][]*VerifiedPredicateResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &target))
+		require.Len(t, target, 1)
+		for _, results := range target {
+			require.Len(t, results, 2)
+		}
+	})
+
+	t.Run("with an online-then-offline handoff", func(t *testing.T) {
+		onlineOpts := publicGoodOpts
+		require.Nil(t, runVerify(&onlineOpts))
+
+		offlineOpts := publicGoodOpts
+		offlineOpts.Offline = true
+		offlineOpts.APIClient = nil
+		offlineOpts.TrustedRoot = test.NormalizeRelativePath("../test/data/trusted_root.json")
+
+		require.Nil(t, runVerify(&offlineOpts))
+	})
+
+	t.Run("with a refresh-style tuf-cache-dir in place of --trusted-root", func(t *testing.T) {
+		offlineOpts := publicGoodOpts
+		offlineOpts.Offline = true
+		offlineOpts.APIClient = nil
+		offlineOpts.TUFCacheDir = test.NormalizeRelativePath("../test/data")
+
+		require.Nil(t, runVerify(&offlineOpts))
+	})
+
+	t.Run("with offline mode and no bundle", func(t *testing.T) {
+		opts := publicGoodOpts
+		opts.Offline = true
+		opts.APIClient = nil
+		opts.BundlePath = ""
+		opts.TrustedRoot = test.NormalizeRelativePath("../test/data/trusted_root.json")
+
+		err := runVerify(&opts)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "--offline requires --bundle")
+	})
+
+	t.Run("with a batch containing one failing artifact", func(t *testing.T) {
+		opts := publicGoodOpts
+		opts.ArtifactPath = ""
+		opts.ArtifactPaths = []string{artifactPath, "../test/data/non-existent-artifact.zip"}
+		opts.Jobs = 2
+
+		err := runVerify(&opts)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "non-existent-artifact.zip")
+	})
 }
 
 func TestVerifySLSAPredicateType_InvalidPredicate(t *testing.T) {
@@ -399,3 +677,43 @@ func TestVerifySLSAPredicateType_InvalidPredicate(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorIs(t, err, ErrNoMatchingSLSAPredicate)
 }
+
+func TestVerifyPredicateTypes(t *testing.T) {
+	spdxStatement := &in_toto.Statement{}
+	spdxStatement.PredicateType = SPDXPredicateType
+	spdxStatement.Predicate = map[string]any{"spdxVersion": "SPDX-2.3"}
+
+	apr := []*verification.AttestationProcessingResult{
+		{
+			VerificationResult: &verify.VerificationResult{
+				Statement: spdxStatement,
+			},
+		},
+	}
+
+	t.Run("matches a requested predicate type and enforces its policy", func(t *testing.T) {
+		annotated, err := verifyPredicateTypes(io.NewTestHandler(), apr, []string{SPDXPredicateType})
+		require.NoError(t, err)
+		require.Len(t, annotated, 1)
+		require.Equal(t, []string{SPDXPredicateType}, annotated[0].MatchedPredicateTypes)
+	})
+
+	t.Run("fails when the SBOM predicate is missing its required field", func(t *testing.T) {
+		invalidStatement := &in_toto.Statement{}
+		invalidStatement.PredicateType = SPDXPredicateType
+		invalidStatement.Predicate = map[string]any{}
+		invalidAPR := []*verification.AttestationProcessingResult{
+			{VerificationResult: &verify.VerificationResult{Statement: invalidStatement}},
+		}
+
+		_, err := verifyPredicateTypes(io.NewTestHandler(), invalidAPR, []string{SPDXPredicateType})
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrPredicateTypePolicyFailed)
+	})
+
+	t.Run("fails when no attestation matches the requested predicate type", func(t *testing.T) {
+		_, err := verifyPredicateTypes(io.NewTestHandler(), apr, []string{CycloneDXPredicateType})
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrNoMatchingPredicateType)
+	})
+}